@@ -0,0 +1,46 @@
+// Package content turns a fetched page's raw HTML into scored, rankable
+// text: main-content extraction, tokenization, and corpus-wide TF-IDF.
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	readability "codeberg.org/readeck/go-readability/v2"
+)
+
+// Article is the main-content extraction of a crawled page, with
+// boilerplate (nav, footer, ads) stripped out.
+type Article struct {
+	Title       string
+	Byline      string
+	Excerpt     string
+	TextContent string
+}
+
+// Extract parses rawHTML with go-readability, anchored at pageURL so
+// relative links resolve correctly when judging content density.
+func Extract(rawHTML, pageURL string) (Article, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return Article{}, fmt.Errorf("invalid page URL: %w", err)
+	}
+
+	article, err := readability.FromReader(strings.NewReader(rawHTML), u)
+	if err != nil {
+		return Article{}, fmt.Errorf("extracting main content: %w", err)
+	}
+
+	var text strings.Builder
+	if err := article.RenderText(&text); err != nil {
+		return Article{}, fmt.Errorf("rendering article text: %w", err)
+	}
+
+	return Article{
+		Title:       article.Title(),
+		Byline:      article.Byline(),
+		Excerpt:     article.Excerpt(),
+		TextContent: text.String(),
+	}, nil
+}
@@ -0,0 +1,9 @@
+package content
+
+import "github.com/kljensen/snowball/english"
+
+// stem reduces a word to its Snowball stem, e.g. "running" -> "run", so
+// "crawl", "crawling", and "crawled" all score as the same term.
+func stem(word string) string {
+	return english.Stem(word, false)
+}
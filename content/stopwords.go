@@ -0,0 +1,14 @@
+package content
+
+// stopwords are common English function words excluded from tokenization so
+// keyword matches and TF-IDF scores reflect content, not grammar.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "can": true, "for": true, "from": true,
+	"has": true, "have": true, "had": true, "he": true, "in": true, "is": true,
+	"it": true, "its": true, "not": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "their": true, "there": true, "they": true,
+	"this": true, "to": true, "was": true, "were": true, "what": true,
+	"when": true, "where": true, "which": true, "who": true, "will": true,
+	"with": true,
+}
@@ -0,0 +1,55 @@
+package content
+
+import (
+	"math"
+	"sort"
+)
+
+// TermFrequency counts occurrences of each token in a single document.
+func TermFrequency(tokens []string) map[string]int {
+	freq := make(map[string]int, len(tokens))
+	for _, t := range tokens {
+		freq[t]++
+	}
+	return freq
+}
+
+// TFIDF scores each term in doc against a corpus described by
+// documentFrequency (how many corpus documents, including doc itself,
+// contain each term) and corpusSize (total documents, including doc).
+func TFIDF(doc map[string]int, documentFrequency map[string]int, corpusSize int) map[string]float64 {
+	scores := make(map[string]float64, len(doc))
+	for term, tf := range doc {
+		idf := math.Log(float64(corpusSize+1) / float64(documentFrequency[term]+1))
+		scores[term] = float64(tf) * idf
+	}
+	return scores
+}
+
+// TopN returns the n terms with the highest score, descending. Ties break on
+// the term itself for a stable order.
+func TopN(scores map[string]float64, n int) []string {
+	type pair struct {
+		term  string
+		score float64
+	}
+	pairs := make([]pair, 0, len(scores))
+	for term, score := range scores {
+		pairs = append(pairs, pair{term, score})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].score != pairs[j].score {
+			return pairs[i].score > pairs[j].score
+		}
+		return pairs[i].term < pairs[j].term
+	})
+
+	if n > len(pairs) {
+		n = len(pairs)
+	}
+	top := make([]string, n)
+	for i := 0; i < n; i++ {
+		top[i] = pairs[i].term
+	}
+	return top
+}
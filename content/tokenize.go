@@ -0,0 +1,37 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lowercased words, dropping punctuation and any
+// word in the stopword list. Set withStemming to additionally reduce each
+// word to its Snowball stem.
+func Tokenize(text string, withStemming bool) []string {
+	return tokenize(text, withStemming, true)
+}
+
+// TokenizeKeyword splits a single user-supplied search keyword the same way
+// Tokenize does, except it never drops stopwords: a keyword like "the" or
+// "for" is a deliberate search term, not incidental grammar to discard.
+func TokenizeKeyword(keyword string, withStemming bool) []string {
+	return tokenize(keyword, withStemming, false)
+}
+
+func tokenize(text string, withStemming, dropStopwords bool) []string {
+	var tokens []string
+	for _, raw := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		word := strings.ToLower(raw)
+		if word == "" || (dropStopwords && stopwords[word]) {
+			continue
+		}
+		if withStemming {
+			word = stem(word)
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
@@ -0,0 +1,402 @@
+// Package crawler implements a breadth-first, multi-page crawl of a site
+// starting from a seed URL. It knows nothing about how pages are scored or
+// persisted - callers supply an onPage callback and get plain Page values
+// back.
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/temoto/robotstxt"
+
+	"github.com/yourname/go-web-crawler/fetchers"
+	"github.com/yourname/go-web-crawler/log"
+)
+
+// Page is a single page discovered and fetched by the Engine.
+type Page struct {
+	URL        string
+	ParentURL  string
+	Depth      int
+	HTML       string
+	Links      []string
+	Screenshot []byte
+	// SeedURL is the seed this page's crawl originated from, letting callers
+	// of CrawlSeeds map a fetched page back to the seed that found it.
+	SeedURL string
+}
+
+// Options bounds a single crawl run.
+type Options struct {
+	// MaxDepth is how many hops from the seed the crawler will follow.
+	// The seed itself is depth 0.
+	MaxDepth int
+	// MaxConcurrency is the number of pages fetched in parallel.
+	MaxConcurrency int
+	// SameOriginOnly restricts discovered links to the seed's host.
+	SameOriginOnly bool
+	// PolitenessDelay is the minimum gap between two requests to the same host.
+	PolitenessDelay time.Duration
+}
+
+// DefaultOptions are the limits CrawlHandler falls back to when the
+// corresponding form fields are blank.
+func DefaultOptions() Options {
+	return Options{
+		MaxDepth:        2,
+		MaxConcurrency:  4,
+		SameOriginOnly:  true,
+		PolitenessDelay: time.Second,
+	}
+}
+
+type frontierItem struct {
+	url    string
+	parent string
+	depth  int
+	// seed is the origin URL this item's crawl started from. Same-origin
+	// scoping is checked against seed rather than a single engine-wide URL,
+	// since CrawlSeeds can run multiple seeds with different hosts at once.
+	seed *url.URL
+}
+
+// frontier is the unbounded work queue CrawlSeeds' worker pool pulls from.
+// It's a plain mutex/condvar-guarded slice rather than a buffered channel so
+// that push never blocks: a channel with a fixed capacity can deadlock once
+// every worker is simultaneously blocked trying to push discovered links
+// while none is left to drain the channel, which a single page with enough
+// same-origin links (or a large sitemap feeding CrawlSeeds thousands of
+// seeds at once) reaches easily.
+type frontier struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue []frontierItem
+	// pending counts items pushed but not yet settled - still queued or
+	// being processed by a worker. The frontier is exhausted once it hits 0.
+	pending int
+	closed  bool
+}
+
+func newFrontier() *frontier {
+	f := &frontier{}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// push adds item to the frontier, growing the queue as needed, and wakes one
+// waiting worker.
+func (f *frontier) push(item frontierItem) {
+	f.mu.Lock()
+	f.pending++
+	f.queue = append(f.queue, item)
+	f.mu.Unlock()
+	f.cond.Signal()
+}
+
+// pop blocks until an item is available or the frontier is exhausted, in
+// which case ok is false.
+func (f *frontier) pop() (item frontierItem, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.queue) == 0 && !f.closed {
+		f.cond.Wait()
+	}
+	if len(f.queue) == 0 {
+		return frontierItem{}, false
+	}
+	item, f.queue = f.queue[0], f.queue[1:]
+	return item, true
+}
+
+// settle marks one previously popped item as fully processed - any children
+// it discovered have already been pushed - closing the frontier and waking
+// every blocked worker once nothing is pending.
+func (f *frontier) settle() {
+	f.mu.Lock()
+	f.pending--
+	if f.pending == 0 {
+		f.closed = true
+	}
+	f.mu.Unlock()
+	f.cond.Broadcast()
+}
+
+// Engine crawls a site breadth-first, honoring robots.txt, per-host
+// politeness delays, and a visited set so each URL is fetched once.
+type Engine struct {
+	fetcher      fetchers.Fetcher
+	robotsClient *http.Client
+	opts         Options
+
+	visitedMu sync.Mutex
+	visited   map[string]bool
+
+	robotsMu sync.Mutex
+	robots   map[string]*robotstxt.RobotsData
+
+	fetchMu   sync.Mutex
+	lastFetch map[string]time.Time
+}
+
+// NewEngine builds an Engine ready to crawl with the given options, fetching
+// every page through fetcher. robots.txt is always fetched with a plain
+// HTTP client regardless of which Fetcher is used for pages.
+func NewEngine(opts Options, fetcher fetchers.Fetcher) *Engine {
+	if fetcher == nil {
+		fetcher = fetchers.NewHTTPFetcher()
+	}
+	return &Engine{
+		fetcher:      fetcher,
+		robotsClient: &http.Client{Timeout: 10 * time.Second},
+		opts:         opts,
+		visited:      make(map[string]bool),
+		robots:       make(map[string]*robotstxt.RobotsData),
+		lastFetch:    make(map[string]time.Time),
+	}
+}
+
+// Crawl walks the site reachable from seed, calling onPage for every page it
+// fetches. It's a convenience wrapper around CrawlSeeds for the common
+// single-seed case; see CrawlSeeds for the concurrency contract onPage must
+// honor.
+func (e *Engine) Crawl(ctx context.Context, seed string, onPage func(Page)) error {
+	if _, err := url.Parse(seed); err != nil {
+		return fmt.Errorf("invalid seed URL: %w", err)
+	}
+	return e.CrawlSeeds(ctx, []string{seed}, onPage)
+}
+
+// CrawlSeeds walks the sites reachable from each of seeds, sharing one
+// frontier and worker pool across all of them rather than crawling each seed
+// in its own sequential pass. Each discovered link is scoped to same-origin
+// against the seed it was found under (not against the other seeds), since
+// seeds can point at different hosts. It blocks until the frontier is
+// drained. onPage is called concurrently from up to MaxConcurrency worker
+// goroutines, so it (and anything it closes over) must be safe for
+// concurrent use. Seed URLs that fail to parse are skipped with a warning
+// rather than aborting the whole run.
+func (e *Engine) CrawlSeeds(ctx context.Context, seeds []string, onPage func(Page)) error {
+	logger := log.FromContext(ctx)
+
+	items := make([]frontierItem, 0, len(seeds))
+	for _, seed := range seeds {
+		seedURL, err := url.Parse(seed)
+		if err != nil {
+			logger.Warn("skipping invalid seed URL", "seed", seed, "error", err)
+			continue
+		}
+		items = append(items, frontierItem{url: seed, depth: 0, seed: seedURL})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	concurrency := e.opts.MaxConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	f := newFrontier()
+	for _, item := range items {
+		f.push(item)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, ok := f.pop()
+				if !ok {
+					return
+				}
+				e.process(ctx, item, onPage, f.push)
+				f.settle()
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (e *Engine) process(ctx context.Context, item frontierItem, onPage func(Page), enqueue func(frontierItem)) {
+	logger := log.FromContext(ctx)
+
+	if !e.markVisited(item.url) {
+		return
+	}
+
+	if !e.allowedByRobots(ctx, item.url) {
+		logger.Debug("skipping URL disallowed by robots.txt", "url", item.url)
+		return
+	}
+
+	e.waitForPoliteness(item.url)
+
+	result, err := e.fetcher.Fetch(ctx, item.url)
+	if err != nil {
+		logger.Warn("fetching URL failed", "url", item.url, "depth", item.depth, "error", err)
+		return
+	}
+	html := result.HTML
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		logger.Warn("parsing fetched HTML failed", "url", item.url, "error", err)
+		return
+	}
+
+	var links []string
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, ok := s.Attr("href")
+		if !ok {
+			return
+		}
+		abs := resolveLink(item.url, href)
+		if abs == "" {
+			return
+		}
+		links = append(links, abs)
+
+		if e.opts.SameOriginOnly && !sameOrigin(item.seed, abs) {
+			return
+		}
+		if item.depth+1 > e.opts.MaxDepth {
+			return
+		}
+		enqueue(frontierItem{url: abs, parent: item.url, depth: item.depth + 1, seed: item.seed})
+	})
+
+	logger.Debug("fetched page", "url", item.url, "depth", item.depth, "parent_url", item.parent, "links_found", len(links))
+
+	onPage(Page{
+		URL:        item.url,
+		ParentURL:  item.parent,
+		Depth:      item.depth,
+		HTML:       html,
+		Links:      links,
+		Screenshot: result.Screenshot,
+		SeedURL:    item.seed.String(),
+	})
+}
+
+func (e *Engine) markVisited(rawURL string) bool {
+	e.visitedMu.Lock()
+	defer e.visitedMu.Unlock()
+	if e.visited[rawURL] {
+		return false
+	}
+	e.visited[rawURL] = true
+	return true
+}
+
+// waitForPoliteness blocks until PolitenessDelay has elapsed since the last
+// request to rawURL's host.
+func (e *Engine) waitForPoliteness(rawURL string) {
+	if e.opts.PolitenessDelay <= 0 {
+		return
+	}
+	host := hostOf(rawURL)
+	if host == "" {
+		return
+	}
+
+	e.fetchMu.Lock()
+	next := e.lastFetch[host].Add(e.opts.PolitenessDelay)
+	if now := time.Now(); next.Before(now) {
+		next = now
+	}
+	e.lastFetch[host] = next
+	e.fetchMu.Unlock()
+
+	if wait := time.Until(next); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+func (e *Engine) allowedByRobots(ctx context.Context, rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := parsed.Host
+
+	e.robotsMu.Lock()
+	data, cached := e.robots[host]
+	e.robotsMu.Unlock()
+
+	if !cached {
+		data = e.fetchRobots(ctx, parsed)
+		e.robotsMu.Lock()
+		e.robots[host] = data
+		e.robotsMu.Unlock()
+	}
+
+	if data == nil {
+		return true
+	}
+	return data.TestAgent(parsed.Path, "go-web-crawler")
+}
+
+func (e *Engine) fetchRobots(ctx context.Context, base *url.URL) *robotstxt.RobotsData {
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", base.Scheme, base.Host)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := e.robotsClient.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	data, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func resolveLink(base, href string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	resolved := baseURL.ResolveReference(ref)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	resolved.Fragment = ""
+	return resolved.String()
+}
+
+func sameOrigin(seed *url.URL, rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return parsed.Host == seed.Host
+}
+
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
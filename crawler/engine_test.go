@@ -0,0 +1,160 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourname/go-web-crawler/fetchers"
+)
+
+func pageHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+func newTestEngine(opts Options) *Engine {
+	opts.PolitenessDelay = 0
+	return NewEngine(opts, fetchers.NewHTTPFetcher())
+}
+
+func TestEngineCrawlRespectsMaxDepth(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", pageHandler(`<a href="/a">a</a>`))
+	mux.HandleFunc("/a", pageHandler(`<a href="/b">b</a>`))
+	mux.HandleFunc("/b", pageHandler(`<a href="/c">c</a>`))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	opts := DefaultOptions()
+	opts.MaxDepth = 1
+	e := newTestEngine(opts)
+
+	var mu sync.Mutex
+	var urls []string
+	if err := e.Crawl(context.Background(), srv.URL, func(p Page) {
+		mu.Lock()
+		urls = append(urls, p.URL)
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+
+	if len(urls) != 2 {
+		t.Fatalf("crawled %d pages, want 2 (depth 0 and 1 only): %v", len(urls), urls)
+	}
+	for _, u := range urls {
+		if u == srv.URL+"/b" {
+			t.Errorf("crawled %s, which is beyond MaxDepth %d", u, opts.MaxDepth)
+		}
+	}
+}
+
+func TestEngineCrawlSameOriginOnly(t *testing.T) {
+	external := httptest.NewServer(pageHandler("external page"))
+	defer external.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", pageHandler(`<a href="/local">local</a><a href="`+external.URL+`">external</a>`))
+	mux.HandleFunc("/local", pageHandler("local page"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	opts := DefaultOptions()
+	opts.MaxDepth = 2
+	opts.SameOriginOnly = true
+	e := newTestEngine(opts)
+
+	var mu sync.Mutex
+	var urls []string
+	if err := e.Crawl(context.Background(), srv.URL, func(p Page) {
+		mu.Lock()
+		urls = append(urls, p.URL)
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+
+	for _, u := range urls {
+		if u == external.URL {
+			t.Errorf("crawled cross-origin URL %s despite SameOriginOnly", u)
+		}
+	}
+	if len(urls) != 2 {
+		t.Errorf("crawled %d pages, want 2 (seed and /local): %v", len(urls), urls)
+	}
+}
+
+func TestEngineCrawlSeedsSharesFrontierAcrossSeeds(t *testing.T) {
+	srvA := httptest.NewServer(pageHandler("page a"))
+	defer srvA.Close()
+	srvB := httptest.NewServer(pageHandler("page b"))
+	defer srvB.Close()
+
+	opts := DefaultOptions()
+	opts.MaxDepth = 0
+	e := newTestEngine(opts)
+
+	var mu sync.Mutex
+	seedOf := map[string]string{}
+	if err := e.CrawlSeeds(context.Background(), []string{srvA.URL, srvB.URL}, func(p Page) {
+		mu.Lock()
+		seedOf[p.URL] = p.SeedURL
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("CrawlSeeds returned error: %v", err)
+	}
+
+	if len(seedOf) != 2 {
+		t.Fatalf("crawled %d pages, want 2 (one per seed): %v", len(seedOf), seedOf)
+	}
+	if seedOf[srvA.URL] != srvA.URL {
+		t.Errorf("SeedURL for %s = %q, want %q", srvA.URL, seedOf[srvA.URL], srvA.URL)
+	}
+	if seedOf[srvB.URL] != srvB.URL {
+		t.Errorf("SeedURL for %s = %q, want %q", srvB.URL, seedOf[srvB.URL], srvB.URL)
+	}
+}
+
+// TestEngineCrawlSeedsManyLinksDoesNotDeadlock guards against the frontier
+// blocking forever once in-flight links exceed a fixed channel buffer - a
+// single page linking to more same-origin URLs than that buffer's capacity
+// used to deadlock every worker mid-enqueue.
+func TestEngineCrawlSeedsManyLinksDoesNotDeadlock(t *testing.T) {
+	const linkCount = 400
+
+	mux := http.NewServeMux()
+	var links string
+	for i := 0; i < linkCount; i++ {
+		path := "/p" + strconv.Itoa(i)
+		links += `<a href="` + path + `">l</a>`
+		mux.HandleFunc(path, pageHandler("leaf"))
+	}
+	mux.HandleFunc("/", pageHandler(links))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	opts := DefaultOptions()
+	opts.MaxDepth = 1
+	opts.MaxConcurrency = 4
+	e := newTestEngine(opts)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Crawl(context.Background(), srv.URL, func(p Page) {})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Crawl returned error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Crawl did not return within 10s - frontier deadlocked")
+	}
+}
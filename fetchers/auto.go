@@ -0,0 +1,32 @@
+package fetchers
+
+import (
+	"context"
+	"time"
+)
+
+// AutoFetcher tries a cheap HTTPFetcher first and falls back to a
+// HeadlessFetcher when the static result looks like an un-rendered SPA
+// shell (see LooksJSRendered).
+type AutoFetcher struct {
+	Static   Fetcher
+	Headless Fetcher
+}
+
+// NewAutoFetcher builds an AutoFetcher with default Static/Headless
+// fetchers, both bounded by timeout.
+func NewAutoFetcher(timeout time.Duration) *AutoFetcher {
+	return &AutoFetcher{
+		Static:   &HTTPFetcher{Timeout: timeout},
+		Headless: &HeadlessFetcher{Timeout: timeout, CaptureScreenshot: true},
+	}
+}
+
+// Fetch implements Fetcher.
+func (f *AutoFetcher) Fetch(ctx context.Context, rawURL string) (Result, error) {
+	result, err := f.Static.Fetch(ctx, rawURL)
+	if err == nil && !LooksJSRendered(result.HTML) {
+		return result, nil
+	}
+	return f.Headless.Fetch(ctx, rawURL)
+}
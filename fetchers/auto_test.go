@@ -0,0 +1,56 @@
+package fetchers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// stubFetcher returns a fixed Result/error and records whether it was called.
+type stubFetcher struct {
+	result Result
+	err    error
+	called bool
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context, rawURL string) (Result, error) {
+	f.called = true
+	return f.result, f.err
+}
+
+func TestAutoFetcherUsesStaticWhenNotJSRendered(t *testing.T) {
+	static := &stubFetcher{result: Result{HTML: "<html><body>" + strings.Repeat("content ", 100) + "</body></html>"}}
+	headless := &stubFetcher{}
+
+	f := &AutoFetcher{Static: static, Headless: headless}
+	result, err := f.Fetch(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if !static.called {
+		t.Error("expected Static fetcher to be called")
+	}
+	if headless.called {
+		t.Error("expected Headless fetcher not to be called for a static-looking page")
+	}
+	if result.HTML != static.result.HTML {
+		t.Errorf("HTML = %q, want the Static fetcher's result", result.HTML)
+	}
+}
+
+func TestAutoFetcherFallsBackToHeadlessWhenJSRendered(t *testing.T) {
+	static := &stubFetcher{result: Result{HTML: "<html><head><script>" + strings.Repeat("x", largeScriptBytes) + "</script></head><body><div id=\"root\"></div></body></html>"}}
+	headless := &stubFetcher{result: Result{HTML: "<html><body>rendered</body></html>"}}
+
+	f := &AutoFetcher{Static: static, Headless: headless}
+	result, err := f.Fetch(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if !headless.called {
+		t.Error("expected Headless fetcher to be called for an empty-body static result")
+	}
+	if result.HTML != headless.result.HTML {
+		t.Errorf("HTML = %q, want the Headless fetcher's result", result.HTML)
+	}
+}
@@ -0,0 +1,20 @@
+// Package fetchers provides pluggable strategies for turning a URL into
+// rendered HTML. CrawlHandler picks an implementation per request based on
+// the render=js|static form value, or lets AutoFetcher decide.
+package fetchers
+
+import "context"
+
+// Result is the outcome of fetching a single URL.
+type Result struct {
+	HTML       string
+	StatusCode int
+	// Screenshot is only populated by fetchers capable of rendering a page,
+	// e.g. HeadlessFetcher.
+	Screenshot []byte
+}
+
+// Fetcher turns a URL into rendered HTML.
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (Result, error)
+}
@@ -0,0 +1,58 @@
+package fetchers
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// HeadlessFetcher renders a page in a headless Chrome instance, waiting for
+// the page to settle before returning the post-JS DOM. Use it for SPAs where
+// HTTPFetcher would see an empty <body>.
+type HeadlessFetcher struct {
+	Timeout time.Duration
+	// NetworkIdleWait is how long to sit idle after navigation before
+	// snapshotting the DOM, giving client-side rendering time to finish.
+	NetworkIdleWait time.Duration
+	// CaptureScreenshot, when set, also returns a full-page PNG screenshot.
+	CaptureScreenshot bool
+}
+
+// NewHeadlessFetcher builds a HeadlessFetcher with sane defaults.
+func NewHeadlessFetcher() *HeadlessFetcher {
+	return &HeadlessFetcher{
+		Timeout:         45 * time.Second,
+		NetworkIdleWait: 500 * time.Millisecond,
+	}
+}
+
+// Fetch implements Fetcher.
+func (f *HeadlessFetcher) Fetch(ctx context.Context, rawURL string) (Result, error) {
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = 45 * time.Second
+	}
+
+	browserCtx, cancelBrowser := chromedp.NewContext(ctx)
+	defer cancelBrowser()
+	taskCtx, cancelTimeout := context.WithTimeout(browserCtx, timeout)
+	defer cancelTimeout()
+
+	var html string
+	tasks := chromedp.Tasks{
+		chromedp.Navigate(rawURL),
+		chromedp.Sleep(f.NetworkIdleWait),
+		chromedp.OuterHTML("html", &html),
+	}
+
+	var screenshot []byte
+	if f.CaptureScreenshot {
+		tasks = append(tasks, chromedp.FullScreenshot(&screenshot, 90))
+	}
+
+	if err := chromedp.Run(taskCtx, tasks); err != nil {
+		return Result{}, err
+	}
+	return Result{HTML: html, StatusCode: 200, Screenshot: screenshot}, nil
+}
@@ -0,0 +1,84 @@
+package fetchers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/brotli"
+)
+
+// HTTPFetcher fetches a URL with net/http and returns the pre-JS DOM. It is
+// the right choice for static pages and is far cheaper than HeadlessFetcher.
+type HTTPFetcher struct {
+	Client  *http.Client
+	Timeout time.Duration
+}
+
+// NewHTTPFetcher builds an HTTPFetcher with sane defaults.
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{Timeout: 30 * time.Second}
+}
+
+// Fetch implements Fetcher.
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) (Result, error) {
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("User-Agent", "go-web-crawler/1.0 (+https://github.com/yourname/go-web-crawler)")
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	switch resp.Header.Get("Content-Encoding") {
+	case "gzip":
+		gzReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return Result{}, err
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	case "br":
+		reader = brotli.NewReader(resp.Body)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return Result{}, err
+	}
+
+	html := string(body)
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body)); err == nil {
+		if rendered, err := doc.Html(); err == nil {
+			html = rendered
+		}
+	}
+
+	result := Result{HTML: html, StatusCode: resp.StatusCode}
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, rawURL)
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package fetchers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPFetcherFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>hello</body></html>"))
+	}))
+	defer srv.Close()
+
+	f := &HTTPFetcher{Timeout: 5 * time.Second}
+	result, err := f.Fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", result.StatusCode, http.StatusOK)
+	}
+	if !strings.Contains(result.HTML, "hello") {
+		t.Errorf("HTML = %q, want it to contain %q", result.HTML, "hello")
+	}
+}
+
+func TestHTTPFetcherFetchNonOK(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	f := &HTTPFetcher{Timeout: 5 * time.Second}
+	result, err := f.Fetch(context.Background(), srv.URL)
+	if err == nil {
+		t.Fatal("Fetch returned nil error for a non-200 response")
+	}
+	if result.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode = %d, want %d", result.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,33 @@
+package fetchers
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	minBodyTextLen   = 200
+	largeScriptBytes = 5000
+)
+
+// LooksJSRendered sniffs a fetched page for signs it needs headless
+// rendering: a near-empty visible body alongside a large inline <script>
+// payload is the classic signature of an un-rendered SPA shell.
+func LooksJSRendered(html string) bool {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return false
+	}
+
+	var scriptBytes int
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		scriptBytes += len(s.Text())
+	})
+
+	// Script and style contents are not visible text, so they are removed
+	// before measuring the body.
+	doc.Find("script, style").Remove()
+	bodyText := strings.TrimSpace(doc.Find("body").Text())
+	return len(bodyText) < minBodyTextLen && scriptBytes >= largeScriptBytes
+}
@@ -0,0 +1,40 @@
+package fetchers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLooksJSRendered(t *testing.T) {
+	bigScript := "<script>" + strings.Repeat("x", largeScriptBytes) + "</script>"
+
+	tests := []struct {
+		name string
+		html string
+		want bool
+	}{
+		{
+			name: "static page with plenty of visible text",
+			html: "<html><body>" + strings.Repeat("content ", 100) + "</body></html>",
+			want: false,
+		},
+		{
+			name: "empty SPA shell with a large inline script",
+			html: "<html><head>" + bigScript + "</head><body><div id=\"root\"></div></body></html>",
+			want: true,
+		},
+		{
+			name: "empty body with no script payload",
+			html: "<html><body></body></html>",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksJSRendered(tt.html); got != tt.want {
+				t.Errorf("LooksJSRendered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
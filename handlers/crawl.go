@@ -1,34 +1,56 @@
 package handlers
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/andybalholm/brotli"
 	"github.com/joho/godotenv"
 
 	"html/template"
 
+	"github.com/yourname/go-web-crawler/content"
+	"github.com/yourname/go-web-crawler/crawler"
+	"github.com/yourname/go-web-crawler/fetchers"
+	"github.com/yourname/go-web-crawler/log"
+	"github.com/yourname/go-web-crawler/seeds"
+
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// topTermCount is how many TF-IDF terms are surfaced on /results per page.
+const topTermCount = 10
+
 type Page struct {
-	URL       string    `bson:"url"`
-	Keywords  []string  `bson:"keywords"`
-	Scores    []Score   `bson:"scores"`
-	HTML      string    `bson:"html"`
-	CrawlTime time.Time `bson:"crawl_time"`
+	URL                string              `bson:"url"`
+	ParentURL          string              `bson:"parent_url,omitempty"`
+	Depth              int                 `bson:"depth"`
+	Keywords           []string            `bson:"keywords"`
+	Scores             []Score             `bson:"scores"`
+	HTML               string              `bson:"html"`
+	Text               string              `bson:"text"`
+	ScreenshotID       *primitive.ObjectID `bson:"screenshot_id,omitempty"`
+	SeedSource         string              `bson:"seed_source,omitempty"`
+	Title              string              `bson:"title"`
+	Byline             string              `bson:"byline,omitempty"`
+	Excerpt            string              `bson:"excerpt,omitempty"`
+	WordCount          int                 `bson:"word_count"`
+	ReadingTimeMinutes int                 `bson:"reading_time_minutes"`
+	Terms              []string            `bson:"terms,omitempty"`
+	TFIDF              map[string]float64  `bson:"tfidf,omitempty"`
+	TopTerms           []string            `bson:"top_terms,omitempty"`
+	CrawlTime          time.Time           `bson:"crawl_time"`
 }
 
 type Score struct {
@@ -37,18 +59,19 @@ type Score struct {
 }
 
 var mongoClient *mongo.Client
+var screenshotBucket *gridfs.Bucket
 
 func init() {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found")
+		slog.Warn(".env file not found")
 	}
 
 	// Get MongoDB URI from environment variable or use default
 	mongoURI := os.Getenv("MONGO_URI")
 	if mongoURI == "" {
 		mongoURI = "mongodb://localhost:27017"
-		log.Println("Using default MongoDB URI")
+		slog.Info("Using default MongoDB URI")
 	}
 
 	var err error
@@ -57,48 +80,168 @@ func init() {
 
 	mongoClient, err = mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		slog.Error("Failed to connect to MongoDB", "error", err)
+		os.Exit(1)
 	}
 
 	// Ping the database
 	err = mongoClient.Ping(ctx, nil)
 	if err != nil {
-		log.Fatal("Failed to ping MongoDB:", err)
+		slog.Error("Failed to ping MongoDB", "error", err)
+		os.Exit(1)
+	}
+
+	screenshotBucket, err = gridfs.NewBucket(mongoClient.Database("crawler"), options.GridFSBucket().SetName("screenshots"))
+	if err != nil {
+		slog.Error("Failed to open screenshots GridFS bucket", "error", err)
+		os.Exit(1)
+	}
+
+	pages := mongoClient.Database("crawler").Collection("pages")
+	if _, err := pages.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "text", Value: "text"}, {Key: "title", Value: "text"}},
+	}); err != nil {
+		slog.Warn("Failed to create text search index", "error", err)
 	}
 }
 
 func IndexHandler(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+
 	tmpl, err := template.ParseFiles("templates/index.html")
 	if err != nil {
+		logger.Error("parsing index template failed", "error", err)
 		http.Error(w, "Failed to parse template", http.StatusInternalServerError)
 		return
 	}
-	err = tmpl.Execute(w, nil)
-	if err != nil {
+	if err := tmpl.Execute(w, nil); err != nil {
+		logger.Error("executing index template failed", "error", err)
 		http.Error(w, "Failed to execute template", http.StatusInternalServerError)
 		return
 	}
 }
 
+// htmlToText strips tags from a fetched page so keyword scoring runs over
+// visible text rather than markup.
+func htmlToText(rawHTML string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		return rawHTML
+	}
+	return doc.Find("body").Text()
+}
+
+// resolveFetcher maps the /submit "render" form value to a fetchers.Fetcher:
+// "static" forces the plain HTTP fetcher, "js" forces headless rendering,
+// and anything else (including blank) lets AutoFetcher sniff each page.
+func resolveFetcher(render string, timeout time.Duration) fetchers.Fetcher {
+	switch strings.ToLower(render) {
+	case "static":
+		return &fetchers.HTTPFetcher{Timeout: timeout}
+	case "js":
+		return &fetchers.HeadlessFetcher{Timeout: timeout, CaptureScreenshot: true}
+	default:
+		return fetchers.NewAutoFetcher(timeout)
+	}
+}
+
+// averageWordsPerMinute is used to estimate ReadingTimeMinutes.
+const averageWordsPerMinute = 200
+
+// documentFrequency loads the unique term set of every previously crawled
+// page, returning how many documents each term appears in and the total
+// document count. It is the corpus TFIDF is scored against.
+func documentFrequency(ctx context.Context, collection *mongo.Collection) (map[string]int, int, error) {
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"terms": 1}))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cur.Close(ctx)
+
+	docFreq := make(map[string]int)
+	corpusSize := 0
+	for cur.Next(ctx) {
+		corpusSize++
+		var doc struct {
+			Terms []string `bson:"terms"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			continue
+		}
+		for _, term := range doc.Terms {
+			docFreq[term]++
+		}
+	}
+	return docFreq, corpusSize, cur.Err()
+}
+
+// recentlyCrawled reports whether pageURL already has a saved copy crawled
+// on or after lastMod, so sitemap entries honor <lastmod> for incremental
+// recrawls instead of re-fetching pages that haven't changed since.
+func recentlyCrawled(ctx context.Context, collection *mongo.Collection, pageURL string, lastMod time.Time) bool {
+	if lastMod.IsZero() {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := collection.CountDocuments(ctx, bson.M{"url": pageURL, "crawl_time": bson.M{"$gte": lastMod}})
+	if err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// saveScreenshot uploads a page screenshot to the screenshots GridFS bucket
+// and returns the resulting file ID.
+func saveScreenshot(pageURL string, screenshot []byte) (primitive.ObjectID, error) {
+	id := primitive.NewObjectID()
+	uploadStream, err := screenshotBucket.OpenUploadStreamWithID(id, pageURL)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	defer uploadStream.Close()
+
+	if _, err := uploadStream.Write(screenshot); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return id, nil
+}
+
+// formInt reads an integer form field of at least minimum, falling back to
+// def when the field is blank, not a valid number, or below minimum.
+func formInt(r *http.Request, field string, def, minimum int) int {
+	raw := r.FormValue(field)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < minimum {
+		return def
+	}
+	return n
+}
+
 func CrawlHandler(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+
 	if r.Method != http.MethodPost {
-		log.Printf("Invalid method: %s, expected POST", r.Method)
+		logger.Warn("invalid method for /submit", "method", r.Method)
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
 
-	url := r.FormValue("url")
-	if url == "" {
-		log.Println("URL is empty")
+	seed := r.FormValue("url")
+	if seed == "" {
+		logger.Warn("url is empty")
 		http.Error(w, "URL is required", http.StatusBadRequest)
 		return
 	}
-	log.Printf("Attempting to crawl URL: %s", url)
 
 	// Get and process keywords
 	keywordsRaw := r.FormValue("keywords")
 	if keywordsRaw == "" {
-		log.Println("Keywords are empty")
+		logger.Warn("keywords are empty")
 		http.Error(w, "Keywords are required", http.StatusBadRequest)
 		return
 	}
@@ -111,149 +254,165 @@ func CrawlHandler(w http.ResponseWriter, r *http.Request) {
 			keywords = append(keywords, keyword)
 		}
 	}
-	log.Printf("Processing keywords: %v", keywords)
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return nil // Allow redirects
-		},
-	}
+	opts := crawler.DefaultOptions()
+	opts.MaxDepth = formInt(r, "MaxDepth", opts.MaxDepth, 0)
+	opts.MaxConcurrency = formInt(r, "MaxConcurrency", opts.MaxConcurrency, 1)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to create request: %v", err), http.StatusInternalServerError)
-		return
-	}
+	perRequestTimeout := time.Duration(formInt(r, "timeout", 30, 1)) * time.Second
+	fetcher := resolveFetcher(r.FormValue("render"), perRequestTimeout)
 
-	// Add more realistic headers
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
+	withStemming := r.FormValue("stem") != "false"
 
-	log.Printf("Sending request with headers: %+v", req.Header)
-
-	resp, err := client.Do(req)
+	discovered, err := seeds.Expand(r.Context(), seed)
 	if err != nil {
-		log.Printf("Error fetching URL: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch URL: %v", err), http.StatusInternalServerError)
-		return
+		logger.Warn("expanding seed failed, crawling it directly", "seed", seed, "error", err)
+		discovered = []seeds.Seed{{URL: seed}}
 	}
-	defer resp.Body.Close()
-
-	log.Printf("Response status code: %d", resp.StatusCode)
-	log.Printf("Response headers: %+v", resp.Header)
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Error: received status code %d for URL: %s", resp.StatusCode, url)
-		http.Error(w, fmt.Sprintf("Failed to fetch URL: status code %d", resp.StatusCode), http.StatusInternalServerError)
-		return
-	}
+	collection := mongoClient.Database("crawler").Collection("pages")
 
-	// Handle different encodings
-	var reader io.Reader = resp.Body
-	switch resp.Header.Get("Content-Encoding") {
-	case "gzip":
-		gzReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			log.Printf("Error creating gzip reader: %v", err)
-			http.Error(w, "Failed to decompress gzip response", http.StatusInternalServerError)
-			return
+	toCrawl := make([]seeds.Seed, 0, len(discovered))
+	for _, sd := range discovered {
+		if recentlyCrawled(r.Context(), collection, sd.URL, sd.LastMod) {
+			logger.Debug("skipping unchanged page", "url", sd.URL, "last_mod", sd.LastMod)
+			continue
 		}
-		defer gzReader.Close()
-		reader = gzReader
-	case "br":
-		reader = brotli.NewReader(resp.Body)
-		log.Printf("Using Brotli decompression")
-	case "deflate":
-		reader = resp.Body // net/http automatically handles deflate
-	default:
-		reader = resp.Body
+		toCrawl = append(toCrawl, sd)
 	}
 
-	// Read the entire response body
-	bodyBytes, err := io.ReadAll(reader)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-		http.Error(w, "Failed to read response body", http.StatusInternalServerError)
-		return
+	logger.Info("starting crawl",
+		"seed", seed,
+		"seed_urls", len(toCrawl),
+		"seed_urls_skipped", len(discovered)-len(toCrawl),
+		"keywords", keywords,
+		"max_depth", opts.MaxDepth,
+		"max_concurrency", opts.MaxConcurrency,
+		"render", r.FormValue("render"),
+		"timeout", perRequestTimeout,
+	)
+
+	engine := crawler.NewEngine(opts, fetcher)
+
+	// sourceBySeed maps a seed URL back to the sitemap/feed it was discovered
+	// from, so onPage can stamp SeedSource despite all seeds now sharing one
+	// crawl.
+	sourceBySeed := make(map[string]string, len(toCrawl))
+	seedURLs := make([]string, 0, len(toCrawl))
+	for _, sd := range toCrawl {
+		sourceBySeed[sd.URL] = sd.Source
+		seedURLs = append(seedURLs, sd.URL)
 	}
 
-	// Create a new reader from the bytes for goquery
-	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyBytes))
+	// The corpus documentFrequency/corpusSize is computed once for the whole
+	// crawl rather than once per page: it's an O(corpus size) Mongo scan, and
+	// onPage below can run pages from this same crawl concurrently, so
+	// re-running it per page would scale Mongo load with pages fetched
+	// instead of just once.
+	docFreqCtx, docFreqCancel := context.WithTimeout(r.Context(), 5*time.Second)
+	docFreq, corpusSize, err := documentFrequency(docFreqCtx, collection)
+	docFreqCancel()
 	if err != nil {
-		log.Printf("Error parsing HTML: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to parse HTML: %v", err), http.StatusInternalServerError)
-		return
+		logger.Warn("computing corpus document frequency failed", "seed", seed, "error", err)
+		docFreq, corpusSize = map[string]int{}, 0
 	}
 
-	// Get the HTML content
-	htmlContent, err := doc.Html()
-	if err != nil {
-		log.Printf("Error getting HTML: %v", err)
-		htmlContent = string(bodyBytes) // fallback to raw bytes
-	}
+	// onPage runs concurrently from up to opts.MaxConcurrency worker
+	// goroutines, so pageCount is an atomic counter rather than a plain int.
+	var pageCount atomic.Int64
+	err = engine.CrawlSeeds(r.Context(), seedURLs, func(cp crawler.Page) {
+		article, err := content.Extract(cp.HTML, cp.URL)
+		if err != nil {
+			logger.Warn("extracting main content failed, falling back to raw text", "url", cp.URL, "error", err)
+			article = content.Article{TextContent: htmlToText(cp.HTML)}
+		}
 
-	// Get all text content
-	textContent := doc.Find("body").Text()
-	textContent = strings.ToLower(textContent)
-
-	// Calculate scores for each keyword
-	var scores []Score
-	log.Printf("\n========== KEYWORD MATCHES ==========")
-	for _, keyword := range keywords {
-		count := strings.Count(textContent, keyword)
-		scores = append(scores, Score{
-			Keyword: keyword,
-			Count:   count,
-		})
-		log.Printf("Keyword '%s' found %d times", keyword, count)
-	}
-	log.Printf("========== END KEYWORD MATCHES ==========\n")
-
-	// Create page record
-	page := Page{
-		URL:       url,
-		Keywords:  keywords,
-		Scores:    scores,
-		HTML:      htmlContent,
-		CrawlTime: time.Now(),
-	}
+		tokens := content.Tokenize(article.TextContent, withStemming)
+		termFreq := content.TermFrequency(tokens)
+
+		var scores []Score
+		for _, keyword := range keywords {
+			keywordTokens := content.TokenizeKeyword(keyword, withStemming)
+			count := 0
+			for _, kt := range keywordTokens {
+				count += termFreq[kt]
+			}
+			scores = append(scores, Score{Keyword: keyword, Count: count})
+		}
 
-	// Save to MongoDB
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+		// documentFrequency above only counts previously saved pages, but
+		// content.TFIDF's documentFrequency/corpusSize are defined as
+		// including the document being scored, so fold this page's own terms
+		// into a per-page copy before scoring it - docFreq itself is shared
+		// read-only across every concurrently scored page in this crawl.
+		pageDocFreq := make(map[string]int, len(docFreq)+len(termFreq))
+		for term, count := range docFreq {
+			pageDocFreq[term] = count
+		}
+		for term := range termFreq {
+			pageDocFreq[term]++
+		}
+		tfidf := content.TFIDF(termFreq, pageDocFreq, corpusSize+1)
 
-	collection := mongoClient.Database("crawler").Collection("pages")
-	_, err = collection.InsertOne(ctx, page)
+		terms := make([]string, 0, len(termFreq))
+		for term := range termFreq {
+			terms = append(terms, term)
+		}
+
+		wordCount := len(tokens)
+		readingTime := wordCount / averageWordsPerMinute
+		if wordCount%averageWordsPerMinute != 0 || readingTime == 0 {
+			readingTime++
+		}
+
+		page := Page{
+			URL:                cp.URL,
+			ParentURL:          cp.ParentURL,
+			Depth:              cp.Depth,
+			Keywords:           keywords,
+			Scores:             scores,
+			HTML:               cp.HTML,
+			Text:               article.TextContent,
+			SeedSource:         sourceBySeed[cp.SeedURL],
+			Title:              article.Title,
+			Byline:             article.Byline,
+			Excerpt:            article.Excerpt,
+			WordCount:          wordCount,
+			ReadingTimeMinutes: readingTime,
+			Terms:              terms,
+			TFIDF:              tfidf,
+			TopTerms:           content.TopN(tfidf, topTermCount),
+			CrawlTime:          time.Now(),
+		}
+
+		if len(cp.Screenshot) > 0 {
+			if id, err := saveScreenshot(cp.URL, cp.Screenshot); err != nil {
+				logger.Warn("saving screenshot to GridFS failed", "url", cp.URL, "error", err)
+			} else {
+				page.ScreenshotID = &id
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := collection.InsertOne(ctx, page); err != nil {
+			logger.Error("saving page to MongoDB failed", "url", cp.URL, "error", err)
+			return
+		}
+		pageCount.Add(1)
+		logger.Debug("saved page", "url", cp.URL, "depth", cp.Depth, "parent_url", cp.ParentURL, "keyword_scores", len(scores))
+	})
 	if err != nil {
-		log.Printf("Error saving to MongoDB: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to save to database: %v", err), http.StatusInternalServerError)
-		return
+		logger.Error("crawl failed", "seed", seed, "error", err)
 	}
-	log.Printf("Successfully saved page to database with %d keywords", len(keywords))
-
-	// Print page statistics
-	log.Printf("\n========== PAGE STATISTICS ==========")
-	log.Printf("Total number of HTML elements: %d", doc.Find("*").Length())
-	log.Printf("Number of links (a tags): %d", doc.Find("a").Length())
-	log.Printf("Number of images (img tags): %d", doc.Find("img").Length())
-	log.Printf("Number of paragraphs (p tags): %d", doc.Find("p").Length())
-	log.Printf("Number of divs: %d", doc.Find("div").Length())
-	log.Printf("Number of spans: %d", doc.Find("span").Length())
-	log.Printf("Number of headers (h1-h6): %d", doc.Find("h1, h2, h3, h4, h5, h6").Length())
-	log.Printf("Number of forms: %d", doc.Find("form").Length())
-	log.Printf("========== END STATISTICS ==========\n")
+	logger.Info("crawl complete", "seed", seed, "seed_urls", len(toCrawl), "pages_saved", pageCount.Load())
 
 	http.Redirect(w, r, "/results", http.StatusSeeOther)
-	log.Println("Redirecting to results page")
 }
 
 func ResultsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -266,6 +425,7 @@ func ResultsHandler(w http.ResponseWriter, r *http.Request) {
 
 	cur, err := collection.Find(ctx, bson.M{}, opts)
 	if err != nil {
+		logger.Error("fetching results failed", "error", err)
 		http.Error(w, fmt.Sprintf("Failed to fetch results: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -273,18 +433,20 @@ func ResultsHandler(w http.ResponseWriter, r *http.Request) {
 
 	var results []Page
 	if err = cur.All(ctx, &results); err != nil {
+		logger.Error("decoding results failed", "error", err)
 		http.Error(w, fmt.Sprintf("Failed to decode results: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	tmpl, err := template.ParseFiles("templates/results.html")
 	if err != nil {
+		logger.Error("parsing results template failed", "error", err)
 		http.Error(w, "Failed to parse template", http.StatusInternalServerError)
 		return
 	}
 
-	err = tmpl.Execute(w, results)
-	if err != nil {
+	if err := tmpl.Execute(w, results); err != nil {
+		logger.Error("executing results template failed", "error", err)
 		http.Error(w, "Failed to execute template", http.StatusInternalServerError)
 		return
 	}
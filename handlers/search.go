@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-web-crawler/content"
+	"github.com/yourname/go-web-crawler/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MatchLevel describes how much of a search query a single Match covers.
+type MatchLevel string
+
+const (
+	MatchLevelNone    MatchLevel = "none"
+	MatchLevelPartial MatchLevel = "partial"
+	MatchLevelFull    MatchLevel = "full"
+)
+
+const (
+	searchResultLimit = 10
+	snippetWindow     = 200
+)
+
+// Match is a single highlighted fragment of a page's text around one or
+// more query term occurrences.
+type Match struct {
+	Value        string     `json:"value"`
+	MatchLevel   MatchLevel `json:"matchLevel"`
+	MatchedWords []string   `json:"matchedWords"`
+}
+
+// SearchHit is a single page returned by SearchHandler.
+type SearchHit struct {
+	URL     string  `json:"url"`
+	Title   string  `json:"title"`
+	Matches []Match `json:"matches"`
+}
+
+// SearchHandler answers /search?q=... with the top-K pages matching q,
+// using the "pages" collection's text index, each with highlighted snippet
+// fragments around the matched terms.
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		logger.Warn("search query is empty")
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := mongoClient.Database("crawler").Collection("pages")
+
+	filter := bson.M{"$text": bson.M{"$search": query}}
+	findOpts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetLimit(searchResultLimit)
+
+	cur, err := collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		logger.Error("search query failed", "query", query, "error", err)
+		http.Error(w, "Search failed", http.StatusInternalServerError)
+		return
+	}
+	defer cur.Close(ctx)
+
+	var pages []Page
+	if err := cur.All(ctx, &pages); err != nil {
+		logger.Error("decoding search results failed", "query", query, "error", err)
+		http.Error(w, "Failed to decode search results", http.StatusInternalServerError)
+		return
+	}
+
+	queryWords := content.Tokenize(query, false)
+
+	hits := make([]SearchHit, 0, len(pages))
+	for _, page := range pages {
+		hits = append(hits, SearchHit{
+			URL:     page.URL,
+			Title:   page.Title,
+			Matches: buildMatches(page.Text, queryWords),
+		})
+	}
+	logger.Info("search complete", "query", query, "hits", len(hits))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(hits); err != nil {
+		logger.Error("encoding search response failed", "query", query, "error", err)
+	}
+}
+
+// snippetRange is the [start, end) byte range of text a Match was built
+// from, used to dedup matches by actual snippet overlap.
+type snippetRange struct{ start, end int }
+
+func (r snippetRange) overlaps(start, end int) bool {
+	return start < r.end && end > r.start
+}
+
+// buildMatches scans text for occurrences of any queryWord and returns one
+// Match per non-overlapping ~snippetWindow-char snippet found, each
+// highlighted with <mark>.
+func buildMatches(text string, queryWords []string) []Match {
+	if text == "" || len(queryWords) == 0 {
+		return nil
+	}
+	lowerText := strings.ToLower(text)
+
+	var matches []Match
+	var emitted []snippetRange
+	for _, word := range queryWords {
+		for idx := 0; ; {
+			pos := strings.Index(lowerText[idx:], word)
+			if pos == -1 {
+				break
+			}
+			pos += idx
+			idx = pos + len(word)
+
+			start, end := snippetBounds(len(text), pos, len(word))
+			if overlapsAny(emitted, start, end) {
+				continue
+			}
+			emitted = append(emitted, snippetRange{start, end})
+
+			snippet := text[start:end]
+			matchedWords := wordsPresent(strings.ToLower(snippet), queryWords)
+			matches = append(matches, Match{
+				Value:        highlight(snippet, queryWords),
+				MatchLevel:   matchLevel(matchedWords, queryWords),
+				MatchedWords: matchedWords,
+			})
+		}
+	}
+	return matches
+}
+
+func overlapsAny(ranges []snippetRange, start, end int) bool {
+	for _, r := range ranges {
+		if r.overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+func snippetBounds(textLen, matchStart, matchLen int) (int, int) {
+	start := matchStart - snippetWindow/2
+	if start < 0 {
+		start = 0
+	}
+	end := matchStart + matchLen + snippetWindow/2
+	if end > textLen {
+		end = textLen
+	}
+	return start, end
+}
+
+// highlight wraps every occurrence of a queryWord in snippet with <mark>.
+func highlight(snippet string, queryWords []string) string {
+	type span struct{ start, end int }
+	lower := strings.ToLower(snippet)
+
+	var spans []span
+	for _, word := range queryWords {
+		for idx := 0; ; {
+			pos := strings.Index(lower[idx:], word)
+			if pos == -1 {
+				break
+			}
+			pos += idx
+			spans = append(spans, span{pos, pos + len(word)})
+			idx = pos + len(word)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	cursor := 0
+	for _, s := range spans {
+		if s.start < cursor {
+			continue // overlaps a span already highlighted
+		}
+		b.WriteString(snippet[cursor:s.start])
+		b.WriteString("<mark>")
+		b.WriteString(snippet[s.start:s.end])
+		b.WriteString("</mark>")
+		cursor = s.end
+	}
+	b.WriteString(snippet[cursor:])
+	return b.String()
+}
+
+func wordsPresent(lowerText string, queryWords []string) []string {
+	var present []string
+	for _, word := range queryWords {
+		if strings.Contains(lowerText, word) {
+			present = append(present, word)
+		}
+	}
+	return present
+}
+
+func matchLevel(matchedWords, queryWords []string) MatchLevel {
+	switch {
+	case len(matchedWords) == 0:
+		return MatchLevelNone
+	case len(matchedWords) == len(queryWords):
+		return MatchLevelFull
+	default:
+		return MatchLevelPartial
+	}
+}
@@ -0,0 +1,65 @@
+// Package log provides the app's leveled, structured logging: JSON output
+// in production and human-readable output in development, built on
+// log/slog, with a per-request logger carrying a request ID and client IP
+// through context (see Middleware).
+package log
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// Init configures the process-wide default logger. mode "production"
+// selects JSON output; anything else (including "") selects human-readable
+// text output for local development. Level is read from the LOG_LEVEL env
+// var (debug, info, warn, error), defaulting to info. When fileSink is
+// non-nil, log lines are written to it in addition to stdout.
+func Init(mode string, fileSink io.Writer) {
+	level := parseLevel(os.Getenv("LOG_LEVEL"))
+
+	out := io.Writer(os.Stdout)
+	if fileSink != nil {
+		out = io.MultiWriter(os.Stdout, fileSink)
+	}
+
+	var handler slog.Handler
+	if mode == "production" {
+		handler = slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level})
+	} else {
+		handler = slog.NewTextHandler(out, &slog.HandlerOptions{Level: level})
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+func parseLevel(raw string) slog.Level {
+	switch strings.ToLower(raw) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// NewContext returns a copy of ctx carrying logger, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logger attached to ctx by Middleware, or the
+// process-wide default logger if none was attached.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Middleware attaches a per-request logger carrying a generated request_id
+// and the client's IP to the request's context, so every handler and
+// downstream package can log with log.FromContext(r.Context()).
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := slog.Default().With(
+			"request_id", uuid.NewString(),
+			"client_ip", clientIP(r),
+		)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), logger)))
+	})
+}
+
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return strings.TrimSpace(strings.SplitN(fwd, ",", 2)[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
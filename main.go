@@ -1,16 +1,34 @@
 package main
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+
+	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/yourname/go-web-crawler/handlers"
+	"github.com/yourname/go-web-crawler/log"
 )
 
 func main() {
-	http.HandleFunc("/", handlers.IndexHandler)
-	http.HandleFunc("/submit", handlers.CrawlHandler)
-	http.HandleFunc("/results", handlers.ResultsHandler)
-	log.Println("Server running at http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	fileSink := &lumberjack.Logger{
+		Filename:   "crawler.log",
+		MaxSize:    100, // megabytes
+		MaxBackups: 5,
+		MaxAge:     28, // days
+	}
+	log.Init(os.Getenv("APP_ENV"), fileSink)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handlers.IndexHandler)
+	mux.HandleFunc("/submit", handlers.CrawlHandler)
+	mux.HandleFunc("/results", handlers.ResultsHandler)
+	mux.HandleFunc("/search", handlers.SearchHandler)
+
+	slog.Info("Server running at http://localhost:8080")
+	if err := http.ListenAndServe(":8080", log.Middleware(mux)); err != nil {
+		slog.Error("server exited", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,253 @@
+// Package seeds resolves a single /submit URL into the set of page URLs a
+// crawl should actually start from. A seed that turns out to be a
+// sitemap.xml or an RSS/Atom feed expands into every URL it lists instead
+// of being crawled as a page itself.
+package seeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kind identifies what sort of document a seed URL resolved to.
+type Kind string
+
+const (
+	KindPage    Kind = "page"
+	KindSitemap Kind = "sitemap"
+	KindFeed    Kind = "feed"
+)
+
+// Seed is a single URL a crawl should start from, along with where it was
+// discovered: Source is the sitemap or feed URL it came from, or "" when it
+// was submitted directly.
+type Seed struct {
+	URL     string
+	Source  string
+	LastMod time.Time
+}
+
+// fetchTimeout bounds how long Expand waits for the seed URL, and any
+// sitemaps it recursively follows, to respond.
+const fetchTimeout = 10 * time.Second
+
+// maxSitemapDepth bounds how many levels of nested <sitemapindex> Expand
+// will follow, so a cyclic or malicious sitemap can't recurse forever.
+const maxSitemapDepth = 5
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// Expand fetches rawURL and, based on its root XML element, either returns
+// it unchanged as a single page seed or expands it into every URL a
+// sitemap or RSS/Atom feed at that address lists.
+func Expand(ctx context.Context, rawURL string) ([]Seed, error) {
+	body, err := fetchBody(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch detect(body) {
+	case KindSitemap:
+		return expandSitemap(ctx, rawURL, body, 0)
+	case KindFeed:
+		return expandFeed(rawURL, body)
+	default:
+		return []Seed{{URL: rawURL}}, nil
+	}
+}
+
+func fetchBody(ctx context.Context, rawURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, rawURL)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// detect sniffs the root XML element of body to tell a sitemap, a feed, and
+// an ordinary page apart. Anything that isn't well-formed XML rooted at one
+// of the recognized element names is treated as a page.
+func detect(body []byte) Kind {
+	switch rootElement(body) {
+	case "urlset", "sitemapindex":
+		return KindSitemap
+	case "rss", "feed":
+		return KindFeed
+	default:
+		return KindPage
+	}
+}
+
+func rootElement(body []byte) string {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type urlSet struct {
+	URLs []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// expandSitemap parses body as either a <urlset> (leaf sitemap) or a
+// <sitemapindex> (pointing at other sitemaps), recursively following nested
+// sitemaps up to maxSitemapDepth.
+func expandSitemap(ctx context.Context, sitemapURL string, body []byte, depth int) ([]Seed, error) {
+	var leaf urlSet
+	if err := xml.Unmarshal(body, &leaf); err == nil && len(leaf.URLs) > 0 {
+		seeds := make([]Seed, 0, len(leaf.URLs))
+		for _, u := range leaf.URLs {
+			loc := strings.TrimSpace(u.Loc)
+			if loc == "" {
+				continue
+			}
+			seeds = append(seeds, Seed{URL: loc, Source: sitemapURL, LastMod: parseLastMod(u.LastMod)})
+		}
+		return seeds, nil
+	}
+
+	var index sitemapIndex
+	if err := xml.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("parsing sitemap %s: %w", sitemapURL, err)
+	}
+	if depth >= maxSitemapDepth {
+		return nil, fmt.Errorf("sitemap index %s nested deeper than %d levels", sitemapURL, maxSitemapDepth)
+	}
+
+	var seeds []Seed
+	for _, child := range index.Sitemaps {
+		loc := strings.TrimSpace(child.Loc)
+		if loc == "" {
+			continue
+		}
+		childBody, err := fetchBody(ctx, loc)
+		if err != nil {
+			continue
+		}
+		childSeeds, err := expandSitemap(ctx, loc, childBody, depth+1)
+		if err != nil {
+			continue
+		}
+		seeds = append(seeds, childSeeds...)
+	}
+	return seeds, nil
+}
+
+// lastModLayouts are the date formats <lastmod> shows up in across sitemaps
+// in the wild: full W3C datetime, and a bare date.
+var lastModLayouts = []string{time.RFC3339, "2006-01-02"}
+
+func parseLastMod(raw string) time.Time {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range lastModLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type rssFeed struct {
+	Channel struct {
+		Items []struct {
+			Link string `xml:"link"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomFeed struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// expandFeed parses body as an RSS or Atom feed and returns the URL each
+// item/entry links to.
+func expandFeed(feedURL string, body []byte) ([]Seed, error) {
+	var rss rssFeed
+	if err := xml.Unmarshal(body, &rss); err == nil && len(rss.Channel.Items) > 0 {
+		seeds := make([]Seed, 0, len(rss.Channel.Items))
+		for _, item := range rss.Channel.Items {
+			link := strings.TrimSpace(item.Link)
+			if link == "" {
+				continue
+			}
+			seeds = append(seeds, Seed{URL: link, Source: feedURL})
+		}
+		return seeds, nil
+	}
+
+	var atom atomFeed
+	if err := xml.Unmarshal(body, &atom); err != nil {
+		return nil, fmt.Errorf("parsing feed %s: %w", feedURL, err)
+	}
+
+	seeds := make([]Seed, 0, len(atom.Entries))
+	for _, entry := range atom.Entries {
+		link := atomEntryLink(entry.Links)
+		if link == "" {
+			continue
+		}
+		seeds = append(seeds, Seed{URL: link, Source: feedURL})
+	}
+	return seeds, nil
+}
+
+// atomEntryLink picks the alternate (human-readable) link out of an Atom
+// entry's <link> elements, falling back to the first one present.
+func atomEntryLink(links []struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}) string {
+	var first string
+	for _, l := range links {
+		href := strings.TrimSpace(l.Href)
+		if href == "" {
+			continue
+		}
+		if first == "" {
+			first = href
+		}
+		if l.Rel == "" || l.Rel == "alternate" {
+			return href
+		}
+	}
+	return first
+}
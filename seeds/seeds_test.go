@@ -0,0 +1,169 @@
+package seeds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func xmlHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(body))
+	}
+}
+
+func TestExpandLeafSitemap(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/sitemap.xml", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%s/a</loc><lastmod>2026-01-15</lastmod></url>
+  <url><loc>%s/b</loc></url>
+</urlset>`, srv.URL, srv.URL)))
+
+	got, err := Expand(context.Background(), srv.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expand returned %d seeds, want 2: %+v", len(got), got)
+	}
+
+	if got[0].URL != srv.URL+"/a" {
+		t.Errorf("got[0].URL = %q, want %q", got[0].URL, srv.URL+"/a")
+	}
+	wantLastMod := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	if !got[0].LastMod.Equal(wantLastMod) {
+		t.Errorf("got[0].LastMod = %v, want %v", got[0].LastMod, wantLastMod)
+	}
+	if got[0].Source != srv.URL+"/sitemap.xml" {
+		t.Errorf("got[0].Source = %q, want %q", got[0].Source, srv.URL+"/sitemap.xml")
+	}
+
+	if !got[1].LastMod.IsZero() {
+		t.Errorf("got[1].LastMod = %v, want zero value (no <lastmod>)", got[1].LastMod)
+	}
+}
+
+func TestExpandSitemapIndexRecursion(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/sitemap1.xml", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<urlset><url><loc>%s/page1</loc></url><url><loc>%s/page2</loc></url></urlset>`, srv.URL, srv.URL)))
+	mux.HandleFunc("/sitemap2.xml", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<urlset><url><loc>%s/page3</loc></url></urlset>`, srv.URL)))
+	mux.HandleFunc("/sitemapindex.xml", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<sitemapindex><sitemap><loc>%s/sitemap1.xml</loc></sitemap><sitemap><loc>%s/sitemap2.xml</loc></sitemap></sitemapindex>`, srv.URL, srv.URL)))
+
+	got, err := Expand(context.Background(), srv.URL+"/sitemapindex.xml")
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expand returned %d seeds, want 3 (pages from both child sitemaps): %+v", len(got), got)
+	}
+
+	want := map[string]bool{srv.URL + "/page1": true, srv.URL + "/page2": true, srv.URL + "/page3": true}
+	for _, s := range got {
+		if !want[s.URL] {
+			t.Errorf("unexpected seed URL %q", s.URL)
+		}
+		delete(want, s.URL)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing expected seed URLs: %v", want)
+	}
+}
+
+func TestExpandRSSFeed(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/feed.xml", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<rss><channel>
+  <item><link>%s/post1</link></item>
+  <item><link>%s/post2</link></item>
+</channel></rss>`, srv.URL, srv.URL)))
+
+	got, err := Expand(context.Background(), srv.URL+"/feed.xml")
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expand returned %d seeds, want 2: %+v", len(got), got)
+	}
+	if got[0].URL != srv.URL+"/post1" || got[1].URL != srv.URL+"/post2" {
+		t.Errorf("got = %+v, want posts in feed order", got)
+	}
+}
+
+func TestExpandAtomFeedPrefersAlternateLink(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/feed.atom", xmlHandler(fmt.Sprintf(`<?xml version="1.0"?>
+<feed>
+  <entry>
+    <id>1</id>
+    <link rel="self" href="%s/post1?format=api"/>
+    <link rel="alternate" href="%s/post1"/>
+  </entry>
+</feed>`, srv.URL, srv.URL)))
+
+	got, err := Expand(context.Background(), srv.URL+"/feed.atom")
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Expand returned %d seeds, want 1: %+v", len(got), got)
+	}
+	if got[0].URL != srv.URL+"/post1" {
+		t.Errorf("got[0].URL = %q, want the alternate link %q", got[0].URL, srv.URL+"/post1")
+	}
+}
+
+func TestExpandPlainPageIsReturnedUnchanged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>hello</body></html>"))
+	}))
+	defer srv.Close()
+
+	got, err := Expand(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].URL != srv.URL || got[0].Source != "" {
+		t.Errorf("Expand(%q) = %+v, want a single unsourced seed for the page itself", srv.URL, got)
+	}
+}
+
+func TestParseLastMod(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want time.Time
+	}{
+		{"rfc3339", "2026-01-15T10:30:00Z", time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"bare date", "2026-01-15", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"empty", "", time.Time{}},
+		{"garbage", "not-a-date", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLastMod(tt.raw)
+			if !got.Equal(tt.want) {
+				t.Errorf("parseLastMod(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}